@@ -0,0 +1,28 @@
+package color
+
+// Ansi is a resolved color value, either a hex code (#ffffff), a palette
+// reference (p:name), or an ANSI color name/number, ready to be written to
+// the terminal by the engine's writer.
+type Ansi string
+
+// ColorTypeNone marks a channel (foreground or background) as transparent:
+// no ANSI code should be emitted for it, letting the terminal's default (or,
+// for a powerline separator, the adjacent segment's color) show through.
+const ColorTypeNone Ansi = "none"
+
+// ParseColor resolves a raw color string - as configured, or as rendered
+// from a color template - to an Ansi value, mapping an empty string and the
+// "none"/"transparent" aliases to ColorTypeNone.
+func ParseColor(text string) Ansi {
+	switch text {
+	case "", "none", "transparent":
+		return ColorTypeNone
+	default:
+		return Ansi(text)
+	}
+}
+
+// IsTransparent reports whether c resolves to no ANSI code being emitted.
+func (c Ansi) IsTransparent() bool {
+	return c == ColorTypeNone
+}
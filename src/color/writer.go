@@ -0,0 +1,49 @@
+package color
+
+import "fmt"
+
+const (
+	foregroundSequence = "\x1b[38;5;%sm"
+	backgroundSequence = "\x1b[48;5;%sm"
+	resetSequence      = "\x1b[0m"
+)
+
+// ForegroundSequence returns the ANSI escape code to switch to c as the
+// foreground color, or the empty string when c is transparent.
+func ForegroundSequence(c Ansi) string {
+	if c.IsTransparent() {
+		return ""
+	}
+
+	return fmt.Sprintf(foregroundSequence, string(c))
+}
+
+// BackgroundSequence returns the ANSI escape code to switch to c as the
+// background color, or the empty string when c is transparent.
+func BackgroundSequence(c Ansi) string {
+	if c.IsTransparent() {
+		return ""
+	}
+
+	return fmt.Sprintf(backgroundSequence, string(c))
+}
+
+// Reset returns the ANSI escape code that resets all color attributes.
+func Reset() string {
+	return resetSequence
+}
+
+// PowerlineSeparator returns symbol colored as a powerline separator: the
+// foreground equals the current segment's background, since the separator
+// sits on the previous segment's background and points into the next one.
+// When that background is transparent, the adjacent segment's background is
+// used instead, so the separator still blends into its neighbour rather than
+// being reset to the terminal default.
+func PowerlineSeparator(symbol string, background, adjacentBackground Ansi) string {
+	fg := background
+	if fg.IsTransparent() {
+		fg = adjacentBackground
+	}
+
+	return ForegroundSequence(fg) + symbol
+}
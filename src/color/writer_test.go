@@ -0,0 +1,58 @@
+package color
+
+import "testing"
+
+func TestForegroundSequence(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Color    Ansi
+		Expected string
+	}{
+		{Case: "regular foreground", Color: "123", Expected: "\x1b[38;5;123m"},
+		{Case: "transparent foreground", Color: ColorTypeNone, Expected: ""},
+		{Case: "empty resolves transparent", Color: ParseColor(""), Expected: ""},
+		{Case: "explicit transparent literal", Color: ParseColor("transparent"), Expected: ""},
+	}
+
+	for _, tc := range cases {
+		got := ForegroundSequence(tc.Color)
+		if got != tc.Expected {
+			t.Errorf("%s: ForegroundSequence(%q) = %q, want %q", tc.Case, tc.Color, got, tc.Expected)
+		}
+	}
+}
+
+func TestPowerlineSeparatorHonorsTransparentSide(t *testing.T) {
+	cases := []struct {
+		Case               string
+		Background         Ansi
+		AdjacentBackground Ansi
+		Expected           string
+	}{
+		{
+			Case:               "opaque background wins",
+			Background:         "123",
+			AdjacentBackground: "45",
+			Expected:           "\x1b[38;5;123m",
+		},
+		{
+			Case:               "transparent background falls back to adjacent",
+			Background:         ColorTypeNone,
+			AdjacentBackground: "45",
+			Expected:           "\x1b[38;5;45m",
+		},
+		{
+			Case:               "both transparent emits no color",
+			Background:         ColorTypeNone,
+			AdjacentBackground: ColorTypeNone,
+			Expected:           "",
+		},
+	}
+
+	for _, tc := range cases {
+		got := PowerlineSeparator("", tc.Background, tc.AdjacentBackground)
+		if got != tc.Expected {
+			t.Errorf("%s: PowerlineSeparator() = %q, want %q", tc.Case, got, tc.Expected)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package config
+
+// Block is a horizontal row of segments in the prompt.
+type Block struct {
+	Segments []*Segment `json:"segments"`
+}
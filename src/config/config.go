@@ -0,0 +1,13 @@
+package config
+
+// Config is the user's full prompt configuration.
+type Config struct {
+	Blocks []*Block `json:"blocks"`
+}
+
+// Validate builds the cross-segment dependency graph for every segment in
+// every block, returning an error when the segments' `.Segments.X`
+// references form a cycle, before anything is rendered.
+func (cfg *Config) Validate() (*Graph, error) {
+	return NewGraph(cfg.Blocks)
+}
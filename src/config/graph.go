@@ -0,0 +1,193 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultWorkers bounds how many segments render concurrently when a caller
+// doesn't pick a pool size explicitly.
+const defaultWorkers = 4
+
+type graphNode struct {
+	id      string
+	segment *Segment
+	done    chan struct{}
+	output  string
+	err     error
+}
+
+// Graph is the cross-segment dependency graph derived from every segment's
+// Needs, across every block of a config. Building it validates the config is
+// free of circular `.Segments.X` references; evaluating it renders every
+// enabled segment exactly once, running independent segments concurrently while
+// guaranteeing a segment's dependencies finish before it starts.
+type Graph struct {
+	nodes map[string]*graphNode
+	// order is the topological evaluation order, dependencies before
+	// dependents; kept around mainly so callers/tests can assert on it.
+	order []string
+}
+
+// NewGraph builds the dependency graph for every segment in blocks and
+// topologically sorts it, returning an error naming the offending segments
+// if it contains a cycle.
+func NewGraph(blocks []*Block) (*Graph, error) {
+	nodes := make(map[string]*graphNode)
+
+	for _, block := range blocks {
+		for _, segment := range block.Segments {
+			segment.evaluateNeeds()
+
+			id := segment.identity()
+			if _, exists := nodes[id]; exists {
+				return nil, fmt.Errorf("duplicate segment identity %q", id)
+			}
+
+			nodes[id] = &graphNode{id: id, segment: segment, done: make(chan struct{})}
+		}
+	}
+
+	order, err := topologicalSort(nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Graph{nodes: nodes, order: order}, nil
+}
+
+// topologicalSort orders nodes so every dependency precedes its dependents,
+// returning a descriptive error for the first cycle it finds.
+func topologicalSort(nodes map[string]*graphNode) ([]string, error) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int, len(nodes))
+	order := make([]string, 0, len(nodes))
+	var path []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(append([]string{}, path...), id)
+			return fmt.Errorf("circular segment dependency: %s", strings.Join(cycle, " -> "))
+		}
+
+		state[id] = visiting
+		path = append(path, id)
+
+		if node, ok := nodes[id]; ok {
+			for _, dep := range node.segment.Needs {
+				if _, exists := nodes[dep]; !exists {
+					// reference to a segment outside this config; nothing to schedule
+					continue
+				}
+
+				if err := visit(dep); err != nil {
+					return err
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[id] = visited
+		order = append(order, id)
+
+		return nil
+	}
+
+	// sort ids first so the walk - and any cycle error it produces - is deterministic
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// Evaluate renders every segment in the graph, rendering at most workers
+// segments at a time. A segment only starts once every segment it Needs
+// has finished; each segment is rendered exactly once regardless of how
+// many dependents reference it, and a segment whose writer reports itself
+// disabled is not rendered at all.
+func (g *Graph) Evaluate(workers int) error {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(len(g.nodes))
+
+	for _, n := range g.nodes {
+		go func(n *graphNode) {
+			defer wg.Done()
+
+			for _, dep := range n.segment.Needs {
+				if depNode, ok := g.nodes[dep]; ok {
+					<-depNode.done
+				}
+			}
+
+			sem <- struct{}{}
+
+			if n.segment.writer == nil || n.segment.writer.Enabled() {
+				n.output, n.err = n.segment.Render(g.dependencyOutputs(n.segment.Needs))
+			}
+
+			<-sem
+			close(n.done)
+		}(n)
+	}
+
+	wg.Wait()
+
+	for _, id := range g.order {
+		if n := g.nodes[id]; n.err != nil {
+			return fmt.Errorf("segment %s: %w", id, n.err)
+		}
+	}
+
+	return nil
+}
+
+func (g *Graph) dependencyOutputs(needs []string) map[string]SegmentWriter {
+	writers := make(map[string]SegmentWriter, len(needs))
+
+	for _, id := range needs {
+		if n, ok := g.nodes[id]; ok {
+			writers[id] = n.segment.writer
+		}
+	}
+
+	return writers
+}
+
+// Output returns the cached, rendered template for the segment identified by
+// id, blocking until Evaluate has finished that segment.
+func (g *Graph) Output(id string) (string, error) {
+	n, ok := g.nodes[id]
+	if !ok {
+		return "", fmt.Errorf("unknown segment %q", id)
+	}
+
+	<-n.done
+
+	return n.output, n.err
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+)
+
+func indexOf(order []string, id string) int {
+	for i, o := range order {
+		if o == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func TestGraphExecutionOrder(t *testing.T) {
+	git := &Segment{ID: "Git", Template: "git"}
+	status := &Segment{ID: "Status", Template: "{{ .Segments.Git }} status"}
+	prompt := &Segment{ID: "Prompt", Template: "{{ .Segments.Status }} prompt"}
+
+	// deliberately out of dependency order, to prove the sort - not the
+	// config's declaration order - drives evaluation order
+	graph, err := NewGraph([]*Block{{Segments: []*Segment{prompt, status, git}}})
+	if err != nil {
+		t.Fatalf("NewGraph: %v", err)
+	}
+
+	gitIdx, statusIdx, promptIdx := indexOf(graph.order, "Git"), indexOf(graph.order, "Status"), indexOf(graph.order, "Prompt")
+	if !(gitIdx < statusIdx && statusIdx < promptIdx) {
+		t.Fatalf("expected order Git -> Status -> Prompt, got %v", graph.order)
+	}
+}
+
+func TestGraphCycleRejected(t *testing.T) {
+	git := &Segment{ID: "Git", Template: "{{ .Segments.Status }}"}
+	status := &Segment{ID: "Status", Template: "{{ .Segments.Git }}"}
+
+	_, err := NewGraph([]*Block{{Segments: []*Segment{git, status}}})
+	if err == nil {
+		t.Fatal("expected a circular dependency error, got nil")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "Git") || !strings.Contains(msg, "Status") {
+		t.Fatalf("expected error to name Git and Status, got %q", msg)
+	}
+}
+
+// barrierWriter is a fake SegmentWriter. Evaluate calls Enabled() as the
+// real gate before rendering, and this fake piggybacks on that call to
+// rendezvous with its sibling through arrived/release, so the test can prove
+// both started before either finished rather than running one after the
+// other. It always reports itself enabled.
+type barrierWriter struct {
+	name    string
+	arrived chan string
+	release chan struct{}
+}
+
+func (b *barrierWriter) Init(_ properties.Properties, _ runtime.Environment) {}
+
+func (b *barrierWriter) Enabled() bool {
+	b.arrived <- b.name
+	<-b.release
+
+	return true
+}
+
+func (b *barrierWriter) Template() string {
+	return ""
+}
+
+func TestGraphIndependentSegmentsRunConcurrently(t *testing.T) {
+	arrived := make(chan string, 2)
+	release := make(chan struct{})
+
+	segA := &Segment{ID: "A", writer: &barrierWriter{name: "A", arrived: arrived, release: release}}
+	segB := &Segment{ID: "B", writer: &barrierWriter{name: "B", arrived: arrived, release: release}}
+
+	graph, err := NewGraph([]*Block{{Segments: []*Segment{segA, segB}}})
+	if err != nil {
+		t.Fatalf("NewGraph: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- graph.Evaluate(2) }()
+
+	seen := make(map[string]bool)
+	timeout := time.After(2 * time.Second)
+
+	for len(seen) < 2 {
+		select {
+		case name := <-arrived:
+			seen[name] = true
+		case <-timeout:
+			t.Fatal("timed out waiting for both independent segments to start concurrently")
+		}
+	}
+
+	close(release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+}
+
+func TestGraphCachesOutputPerDependent(t *testing.T) {
+	var calls int
+
+	shared := &Segment{ID: "Shared", Template: "v"}
+	shared.writer = &countingWriter{calls: &calls}
+
+	depA := &Segment{ID: "A", Template: "{{ .Segments.Shared }}"}
+	depB := &Segment{ID: "B", Template: "{{ .Segments.Shared }}"}
+
+	graph, err := NewGraph([]*Block{{Segments: []*Segment{shared, depA, depB}}})
+	if err != nil {
+		t.Fatalf("NewGraph: %v", err)
+	}
+
+	if err := graph.Evaluate(4); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the shared segment to be computed once, got %d calls", calls)
+	}
+}
+
+type countingWriter struct {
+	calls *int
+}
+
+func (w *countingWriter) Init(_ properties.Properties, _ runtime.Environment) {}
+
+func (w *countingWriter) Enabled() bool {
+	*w.calls++
+	return true
+}
+
+func (w *countingWriter) Template() string {
+	return ""
+}
+
+// disabledWriter reports itself as never enabled, so Evaluate must skip
+// rendering its segment's template entirely rather than running it anyway.
+type disabledWriter struct{}
+
+func (w *disabledWriter) Init(_ properties.Properties, _ runtime.Environment) {}
+
+func (w *disabledWriter) Enabled() bool {
+	return false
+}
+
+func (w *disabledWriter) Template() string {
+	return "should not be rendered"
+}
+
+func TestGraphSkipsDisabledSegment(t *testing.T) {
+	segment := &Segment{ID: "Aws", Template: "{{ .Profile }}"}
+	segment.writer = &disabledWriter{}
+
+	graph, err := NewGraph([]*Block{{Segments: []*Segment{segment}}})
+	if err != nil {
+		t.Fatalf("NewGraph: %v", err)
+	}
+
+	if err := graph.Evaluate(1); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	output, err := graph.Output("Aws")
+	if err != nil {
+		t.Fatalf("Output: %v", err)
+	}
+
+	if output != "" {
+		t.Fatalf("expected a disabled segment to render as empty, got %q", output)
+	}
+}
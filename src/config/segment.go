@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/jandedobbeleer/oh-my-posh/src/color"
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+	"github.com/jandedobbeleer/oh-my-posh/src/segments"
+)
+
+// SegmentType identifies which SegmentWriter a segment is backed by.
+type SegmentType string
+
+const (
+	SESSION SegmentType = "session"
+	AWS     SegmentType = "aws"
+)
+
+// SegmentStyle controls how a segment is rendered relative to its neighbours.
+type SegmentStyle string
+
+const (
+	Plain     SegmentStyle = "plain"
+	Powerline SegmentStyle = "powerline"
+	Diamond   SegmentStyle = "diamond"
+)
+
+// SegmentWriter is implemented by every segment in the segments package.
+type SegmentWriter interface {
+	Init(props properties.Properties, env runtime.Environment)
+	Enabled() bool
+	Template() string
+}
+
+var segmentWriters = map[SegmentType]func() SegmentWriter{
+	SESSION: func() SegmentWriter { return &segments.Session{} },
+	AWS:     func() SegmentWriter { return &segments.Aws{} },
+}
+
+// Segment is a single segment of a prompt block, as configured in the user's config file.
+type Segment struct {
+	Type            SegmentType  `json:"type"`
+	Style           SegmentStyle `json:"style,omitempty"`
+	PowerlineSymbol string       `json:"powerline_symbol,omitempty"`
+
+	Foreground          color.Ansi `json:"foreground,omitempty"`
+	Background          color.Ansi `json:"background,omitempty"`
+	ForegroundTemplates []string   `json:"foreground_templates,omitempty"`
+	BackgroundTemplates []string   `json:"background_templates,omitempty"`
+
+	Properties properties.Properties `json:"properties,omitempty"`
+
+	IncludeFolders []string `json:"include_folders,omitempty"`
+	ExcludeFolders []string `json:"exclude_folders,omitempty"`
+
+	Template string `json:"template,omitempty"`
+
+	// ID identifies this segment as a dependency target for other segments'
+	// `.Segments.ID` references. Defaults to the title-cased Type when unset.
+	ID string `json:"id,omitempty"`
+
+	// Needs lists the other segments (by identity) this segment's templates
+	// reference via `.Segments.X`, as discovered by evaluateNeeds.
+	Needs []string `json:"-"`
+
+	writer SegmentWriter
+	env    runtime.Environment
+}
+
+// identity returns the key other segments use to reference this one via
+// `.Segments.X`: ID when set, otherwise the title-cased segment Type.
+func (segment *Segment) identity() string {
+	if len(segment.ID) != 0 {
+		return segment.ID
+	}
+
+	name := string(segment.Type)
+	if len(name) == 0 {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// MapSegmentWithWriter resolves and initializes the SegmentWriter for this
+// segment's Type, returning an error if the type is unknown.
+func (segment *Segment) MapSegmentWithWriter(env runtime.Environment) error {
+	segment.env = env
+
+	factory, ok := segmentWriters[segment.Type]
+	if !ok {
+		return fmt.Errorf("unable to map writer for segment type %s", segment.Type)
+	}
+
+	segment.writer = factory()
+	segment.writer.Init(segment.Properties, env)
+
+	return nil
+}
+
+// shouldIncludeFolder reports whether the current working directory passes
+// this segment's include/exclude folder filters. An exclude match always
+// wins over an include match.
+func (segment *Segment) shouldIncludeFolder() bool {
+	cwd := segment.env.Pwd()
+
+	if len(segment.IncludeFolders) != 0 && !segment.env.DirMatchesOneOf(cwd, segment.IncludeFolders) {
+		return false
+	}
+
+	return !segment.env.DirMatchesOneOf(cwd, segment.ExcludeFolders)
+}
+
+// ResolveForeground returns the first ForegroundTemplates entry that renders
+// to a non-empty string, falling back to Foreground.
+func (segment *Segment) ResolveForeground() color.Ansi {
+	return segment.resolveColor(segment.Foreground, segment.ForegroundTemplates)
+}
+
+// ResolveBackground returns the first BackgroundTemplates entry that renders
+// to a non-empty string, falling back to Background.
+func (segment *Segment) ResolveBackground() color.Ansi {
+	return segment.resolveColor(segment.Background, segment.BackgroundTemplates)
+}
+
+func (segment *Segment) resolveColor(defaultColor color.Ansi, templates []string) color.Ansi {
+	for _, tmpl := range templates {
+		text, err := segment.renderColorTemplate(tmpl)
+		if err != nil || len(text) == 0 {
+			// an empty render means this template doesn't apply; fall
+			// through to the next one rather than going transparent.
+			continue
+		}
+
+		if text == "none" || text == "transparent" {
+			return color.ColorTypeNone
+		}
+
+		return color.Ansi(text)
+	}
+
+	return color.ParseColor(string(defaultColor))
+}
+
+var colorFuncs = template.FuncMap{
+	"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+}
+
+func (segment *Segment) renderColorTemplate(tmpl string) (string, error) {
+	t, err := template.New("color").Funcs(colorFuncs).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, segment.writer); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Render executes this segment's Template, exposing its own writer's
+// exported fields at the top level and the already-rendered dependencies
+// passed in via `.Segments.<identity>`. When the config doesn't override
+// Template, the writer's own default Template() is used instead.
+func (segment *Segment) Render(segments map[string]SegmentWriter) (string, error) {
+	tmpl := segment.Template
+	if len(tmpl) == 0 && segment.writer != nil {
+		tmpl = segment.writer.Template()
+	}
+
+	if len(tmpl) == 0 {
+		return "", nil
+	}
+
+	t, err := template.New(segment.identity()).Funcs(colorFuncs).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, templateData(segment.writer, segments)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// templateData flattens writer's exported fields into a map so a template
+// can address them directly ({{ .Profile }}) alongside a Segments map for
+// cross-segment references ({{ .Segments.Git.URL }}).
+func templateData(writer SegmentWriter, segments map[string]SegmentWriter) map[string]any {
+	data := map[string]any{"Segments": segments}
+
+	if writer == nil {
+		return data
+	}
+
+	v := reflect.ValueOf(writer)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return data
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		data[field.Name] = v.Field(i).Interface()
+	}
+
+	return data
+}
+
+var needsRegex = regexp.MustCompile(`\.Segments\.([A-Za-z0-9_]+)`)
+
+// evaluateNeeds scans this segment's templates for references to other
+// segments (`.Segments.Name`) and records them, in first-seen order, in Needs.
+func (segment *Segment) evaluateNeeds() {
+	segment.Needs = nil
+	seen := make(map[string]bool)
+
+	record := func(tmpl string) {
+		for _, match := range needsRegex.FindAllStringSubmatch(tmpl, -1) {
+			name := match[1]
+			if seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			segment.Needs = append(segment.Needs, name)
+		}
+	}
+
+	record(segment.Template)
+
+	for _, tmpl := range segment.ForegroundTemplates {
+		record(tmpl)
+	}
+
+	for _, tmpl := range segment.BackgroundTemplates {
+		record(tmpl)
+	}
+}
@@ -137,6 +137,34 @@ func TestGetColors(t *testing.T) {
 			},
 			Profile: "john",
 		},
+		{
+			Case:     "Template - transparent override",
+			Expected: color.ColorTypeNone,
+			Default:  "color",
+			Templates: []string{
+				"{{ if eq .Profile \"guest\" }}transparent{{ end }}",
+			},
+			Profile: "guest",
+		},
+		{
+			Case:     "Template - none override",
+			Expected: color.ColorTypeNone,
+			Default:  "color",
+			Templates: []string{
+				"{{ if eq .Profile \"guest\" }}none{{ end }}",
+			},
+			Profile: "guest",
+		},
+		{
+			Case:     "No template - transparent default",
+			Expected: color.ColorTypeNone,
+			Default:  "transparent",
+		},
+		{
+			Case:     "No template - empty default",
+			Expected: color.ColorTypeNone,
+			Default:  "",
+		},
 	}
 	for _, tc := range cases {
 		segment := &Segment{
@@ -203,3 +231,29 @@ func TestEvaluateNeeds(t *testing.T) {
 		assert.Equal(t, tc.Needs, tc.Segment.Needs, tc.Case)
 	}
 }
+
+func TestSegmentRenderFallsBackToWriterTemplate(t *testing.T) {
+	segment := &Segment{
+		writer: &segments.Aws{
+			Profile: "john",
+			Region:  "eu-west-1",
+		},
+	}
+
+	text, err := segment.Render(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, " john@eu-west-1 ", text)
+}
+
+func TestSegmentRenderPrefersConfiguredTemplate(t *testing.T) {
+	segment := &Segment{
+		Template: "custom",
+		writer: &segments.Aws{
+			Profile: "john",
+		},
+	}
+
+	text, err := segment.Render(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom", text)
+}
@@ -0,0 +1,7 @@
+package properties
+
+// Property is the name of a key inside a segment's properties block.
+type Property string
+
+// Properties holds the segment-specific `properties` object from the config.
+type Properties map[Property]any
@@ -0,0 +1,20 @@
+package runtime
+
+const (
+	WINDOWS = "windows"
+	DARWIN  = "darwin"
+	LINUX   = "linux"
+)
+
+// Environment exposes everything a segment needs to know about the
+// shell/OS it's rendering in. Implementations live in this package
+// (Shell, the real one) and in runtime/mock (for tests).
+type Environment interface {
+	Getenv(key string) string
+	Pwd() string
+	Home() string
+	GOOS() string
+	// DirMatchesOneOf returns true when dir matches at least one of the
+	// glob patterns, anchored against Home() when a pattern starts with ~.
+	DirMatchesOneOf(dir string, patterns []string) bool
+}
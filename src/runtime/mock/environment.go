@@ -0,0 +1,33 @@
+package mock
+
+import "github.com/stretchr/testify/mock"
+
+// Environment is a testify mock of runtime.Environment for use in unit tests.
+type Environment struct {
+	mock.Mock
+}
+
+func (env *Environment) Getenv(key string) string {
+	args := env.Called(key)
+	return args.String(0)
+}
+
+func (env *Environment) Pwd() string {
+	args := env.Called()
+	return args.String(0)
+}
+
+func (env *Environment) Home() string {
+	args := env.Called()
+	return args.String(0)
+}
+
+func (env *Environment) GOOS() string {
+	args := env.Called()
+	return args.String(0)
+}
+
+func (env *Environment) DirMatchesOneOf(dir string, patterns []string) bool {
+	args := env.Called(dir, patterns)
+	return args.Bool(0)
+}
@@ -0,0 +1,177 @@
+package runtime
+
+import (
+	"path"
+	"strings"
+)
+
+// recursive tokens that stand in for "zero or more path components" when
+// used as a whole path segment, e.g. "Projects/**/vendor" or "~/work/.../secret-*".
+func isRecursiveToken(segment string) bool {
+	return segment == "**" || segment == "..."
+}
+
+// DirMatchesOneOf reports whether dir matches any of patterns. Patterns may
+// use standard path.Match globs (*, ?, [...]) per path segment, plus recursive
+// tokens (** or ...), each standing in for zero or more intermediate
+// segments. A leading ~ is expanded against home. Matching is case-insensitive
+// on Windows and Darwin.
+func DirMatchesOneOf(dir string, patterns []string, home, goos string) bool {
+	ci := goos == WINDOWS || goos == DARWIN
+	dirParts := pathParts(dir, ci)
+
+	for _, pattern := range patterns {
+		// only a pattern rooted at "/" or "~" is anchored to the start of
+		// dir; a bare relative pattern like "Projects/**/vendor" may match
+		// starting anywhere along the path, same as if it led with "**/".
+		rooted := strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "~")
+
+		pattern = expandHome(pattern, home)
+		groups, anchoredStart, anchoredEnd := splitRecursive(splitPatternSegments(pattern), rooted)
+
+		if len(groups) == 0 {
+			// pattern was made up entirely of recursive tokens (or was empty)
+			return true
+		}
+
+		if matchAt(groups, 0, dirParts, anchoredStart, anchoredEnd, ci) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func expandHome(pattern, home string) string {
+	if pattern == "~" {
+		return home
+	}
+
+	if strings.HasPrefix(pattern, "~/") {
+		return home + pattern[1:]
+	}
+
+	return pattern
+}
+
+// splitPatternSegments splits a pattern on "/", dropping the empty segments
+// produced by a leading/trailing/doubled separator so absolute and relative
+// patterns compare against the same trimmed part list DirMatchesOneOf builds
+// for the directory under test.
+func splitPatternSegments(pattern string) []string {
+	pattern = strings.ReplaceAll(pattern, "\\", "/")
+
+	var segments []string
+	for _, segment := range strings.Split(pattern, "/") {
+		if segment == "" {
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments
+}
+
+func pathParts(p string, ci bool) []string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	p = strings.Trim(p, "/")
+
+	if ci {
+		p = strings.ToLower(p)
+	}
+
+	if p == "" {
+		return nil
+	}
+
+	return strings.Split(p, "/")
+}
+
+// splitRecursive splits pattern segments into literal groups, breaking on
+// every recursive token. anchoredEnd reports whether the last segment of the
+// original pattern was itself literal (as opposed to a recursive token,
+// which relaxes that end of the match); anchoredStart additionally requires
+// the pattern to have been rooted (a leading "/" or "~") for the first group
+// to be pinned to the start of dir, since an unrooted pattern may float.
+func splitRecursive(segments []string, rooted bool) (groups [][]string, anchoredStart, anchoredEnd bool) {
+	anchoredStart = rooted && (len(segments) == 0 || !isRecursiveToken(segments[0]))
+	anchoredEnd = len(segments) == 0 || !isRecursiveToken(segments[len(segments)-1])
+
+	var current []string
+	for _, segment := range segments {
+		if isRecursiveToken(segment) {
+			if len(current) > 0 {
+				groups = append(groups, current)
+				current = nil
+			}
+			continue
+		}
+
+		current = append(current, segment)
+	}
+
+	if len(current) > 0 {
+		groups = append(groups, current)
+	}
+
+	return groups, anchoredStart, anchoredEnd
+}
+
+// matchAt tries to match groups[gi:] against parts. The first group is
+// anchored to the start of parts when anchoredStart is set, and the last
+// group is anchored to the end of parts when anchoredEnd is set; any other
+// group boundary may be separated by zero or more intermediate parts.
+func matchAt(groups [][]string, gi int, parts []string, anchoredStart, anchoredEnd, ci bool) bool {
+	group := groups[gi]
+	isFirst := gi == 0
+	isLast := gi == len(groups)-1
+
+	tryFrom := func(start int) bool {
+		end := start + len(group)
+		if end > len(parts) || !literalMatch(group, parts[start:end], ci) {
+			return false
+		}
+
+		remaining := parts[end:]
+		if isLast {
+			if anchoredEnd {
+				return len(remaining) == 0
+			}
+
+			return true
+		}
+
+		return matchAt(groups, gi+1, remaining, anchoredStart, anchoredEnd, ci)
+	}
+
+	if isFirst && anchoredStart {
+		return tryFrom(0)
+	}
+
+	for start := 0; start+len(group) <= len(parts); start++ {
+		if tryFrom(start) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func literalMatch(pattern, part []string, ci bool) bool {
+	for i, p := range pattern {
+		candidate := part[i]
+
+		if ci {
+			p = strings.ToLower(p)
+			candidate = strings.ToLower(candidate)
+		}
+
+		matched, err := path.Match(p, candidate)
+		if err != nil || !matched {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,100 @@
+package runtime
+
+import "testing"
+
+func TestDirMatchesOneOf(t *testing.T) {
+	cases := []struct {
+		Case     string
+		Dir      string
+		Patterns []string
+		Home     string
+		GOOS     string
+		Expected bool
+	}{
+		{
+			Case:     "recursive wildcard in the middle, unrooted pattern floats",
+			Dir:      "/home/user/Projects/oh-my-posh/test/vendor",
+			Patterns: []string{"Projects/**/vendor"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "unrooted pattern still requires its trailing literal segment",
+			Dir:      "/home/user/Projects/oh-my-posh/test/other",
+			Patterns: []string{"Projects/**/vendor"},
+			Home:     "/home/user",
+			Expected: false,
+		},
+		{
+			Case:     "recursive wildcard in the middle, anchored",
+			Dir:      "/home/user/Projects/oh-my-posh/test/vendor",
+			Patterns: []string{"/home/user/Projects/**/vendor"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "recursive wildcard at the start",
+			Dir:      "/home/user/work/secret-project",
+			Patterns: []string{"**/secret-*"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "recursive wildcard at the start, no match",
+			Dir:      "/home/user/work/public-project",
+			Patterns: []string{"**/secret-*"},
+			Home:     "/home/user",
+			Expected: false,
+		},
+		{
+			Case:     "recursive wildcard at the end",
+			Dir:      "/home/user/work/nested/vendor",
+			Patterns: []string{"/home/user/work/**"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "tilde expansion with recursive token",
+			Dir:      "/home/user/work/a/b/secret-project",
+			Patterns: []string{"~/work/.../secret-*"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "character class segment",
+			Dir:      "/home/user/Projects/v2/vendor",
+			Patterns: []string{"/home/user/Projects/v[0-9]/vendor"},
+			Home:     "/home/user",
+			Expected: true,
+		},
+		{
+			Case:     "single star does not cross path separators",
+			Dir:      "/home/user/Projects/a/b/vendor",
+			Patterns: []string{"/home/user/Projects/*/vendor"},
+			Home:     "/home/user",
+			Expected: false,
+		},
+		{
+			Case:     "case-insensitive on windows",
+			Dir:      "C:/Users/user/Projects/Vendor",
+			Patterns: []string{"~/Projects/**/vendor"},
+			Home:     "C:/Users/user",
+			GOOS:     WINDOWS,
+			Expected: true,
+		},
+		{
+			Case:     "no match without recursive token",
+			Dir:      "/home/user/Projects/oh-my-posh",
+			Patterns: []string{"Projects/nope"},
+			Home:     "/home/user",
+			Expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		got := DirMatchesOneOf(tc.Dir, tc.Patterns, tc.Home, tc.GOOS)
+		if got != tc.Expected {
+			t.Errorf("%s: DirMatchesOneOf(%q, %v) = %v, want %v", tc.Case, tc.Dir, tc.Patterns, got, tc.Expected)
+		}
+	}
+}
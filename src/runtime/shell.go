@@ -0,0 +1,31 @@
+package runtime
+
+import (
+	"os"
+	goruntime "runtime"
+)
+
+// Shell is the real Environment, backed by the OS the prompt is running in.
+type Shell struct{}
+
+func (s *Shell) Getenv(key string) string {
+	return os.Getenv(key)
+}
+
+func (s *Shell) Pwd() string {
+	dir, _ := os.Getwd()
+	return dir
+}
+
+func (s *Shell) Home() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
+func (s *Shell) GOOS() string {
+	return goruntime.GOOS
+}
+
+func (s *Shell) DirMatchesOneOf(dir string, patterns []string) bool {
+	return DirMatchesOneOf(dir, patterns, s.Home(), s.GOOS())
+}
@@ -0,0 +1,28 @@
+package segments
+
+import (
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+)
+
+// Aws renders the active AWS CLI profile and region.
+type Aws struct {
+	env   runtime.Environment
+	props properties.Properties
+
+	Profile string
+	Region  string
+}
+
+func (a *Aws) Init(props properties.Properties, env runtime.Environment) {
+	a.props = props
+	a.env = env
+}
+
+func (a *Aws) Enabled() bool {
+	return len(a.Profile) != 0
+}
+
+func (a *Aws) Template() string {
+	return " {{ .Profile }}{{ if .Region }}@{{ .Region }}{{ end }} "
+}
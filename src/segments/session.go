@@ -0,0 +1,28 @@
+package segments
+
+import (
+	"github.com/jandedobbeleer/oh-my-posh/src/properties"
+	"github.com/jandedobbeleer/oh-my-posh/src/runtime"
+)
+
+// Session renders the name of the user the prompt is running under; the host
+// name is not shown.
+type Session struct {
+	env   runtime.Environment
+	props properties.Properties
+
+	UserName string
+}
+
+func (s *Session) Init(props properties.Properties, env runtime.Environment) {
+	s.props = props
+	s.env = env
+}
+
+func (s *Session) Enabled() bool {
+	return true
+}
+
+func (s *Session) Template() string {
+	return "{{ .UserName }}"
+}